@@ -0,0 +1,309 @@
+// Package bridge moves funds across the chains registered with an
+// Interactor, so fund managers don't have to bridge USDT by hand. The
+// implementation follows the Hop protocol integration in status-go's
+// services/wallet/bridge package: an L1 bridge contract, a per-L2 AMM
+// wrapper, and a per-L2 SaddleSwap pool used to price the bonder fee and
+// the canonical/hToken leg of a transfer.
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// bondedTransferTimeout bounds how long WaitForBondedTransfer polls the
+// destination chain for the bonder's WithdrawalBonded event.
+const bondedTransferTimeout = 30 * time.Minute
+
+// ChainConfig holds the Hop protocol contract addresses for one chain that
+// has opted into bridging. L1BridgeAddress is only set on the L1 chain;
+// the L2 addresses are only set on L2 chains.
+type ChainConfig struct {
+	ChainId             int
+	L1BridgeAddress     common.Address
+	L2BridgeAddress     common.Address
+	L2AmmWrapperAddress common.Address
+	L2SaddleSwapAddress common.Address
+}
+
+func (c ChainConfig) isL1() bool {
+	return c.L1BridgeAddress != (common.Address{})
+}
+
+type hopChain struct {
+	config     ChainConfig
+	client     bind.ContractBackend
+	l1Bridge   *HopL1Bridge
+	l2Bridge   *HopL2Bridge
+	ammWrapper *HopL2AmmWrapper
+	saddleSwap *HopL2SaddleSwap
+}
+
+// Hop bridges USDT between the chains it was configured with, picking the
+// L1<->L2 or L2<->L2 path Hop supports for the requested source/destination
+// pair.
+type Hop struct {
+	chains map[int]*hopChain
+}
+
+// NewHop attaches the Hop contracts described by configs, one set per chain,
+// using clients[chainId] as the backend for that chain.
+func NewHop(configs map[int]ChainConfig, clients map[int]bind.ContractBackend) (*Hop, error) {
+	chains := make(map[int]*hopChain, len(configs))
+	for chainId, cfg := range configs {
+		client, ok := clients[chainId]
+		if !ok {
+			return nil, fmt.Errorf("bridge: no rpc client configured for chain %d", chainId)
+		}
+		hc := &hopChain{config: cfg, client: client}
+
+		if cfg.isL1() {
+			l1Bridge, err := NewHopL1Bridge(cfg.L1BridgeAddress, client)
+			if err != nil {
+				return nil, fmt.Errorf("bridge: failed to attach L1 bridge on chain %d: %v", chainId, err)
+			}
+			hc.l1Bridge = l1Bridge
+		} else {
+			l2Bridge, err := NewHopL2Bridge(cfg.L2BridgeAddress, client)
+			if err != nil {
+				return nil, fmt.Errorf("bridge: failed to attach L2 bridge on chain %d: %v", chainId, err)
+			}
+			ammWrapper, err := NewHopL2AmmWrapper(cfg.L2AmmWrapperAddress, client)
+			if err != nil {
+				return nil, fmt.Errorf("bridge: failed to attach L2 AMM wrapper on chain %d: %v", chainId, err)
+			}
+			saddleSwap, err := NewHopL2SaddleSwap(cfg.L2SaddleSwapAddress, client)
+			if err != nil {
+				return nil, fmt.Errorf("bridge: failed to attach L2 SaddleSwap on chain %d: %v", chainId, err)
+			}
+			hc.l2Bridge = l2Bridge
+			hc.ammWrapper = ammWrapper
+			hc.saddleSwap = saddleSwap
+		}
+
+		chains[chainId] = hc
+	}
+	return &Hop{chains: chains}, nil
+}
+
+// Send submits the source-chain leg of a USDT transfer from srcChainId to
+// dstChainId and returns its transaction. Call WaitForBondedTransfer
+// afterwards to learn when the bonder has delivered funds on the
+// destination chain.
+func (h *Hop) Send(
+	ctx context.Context,
+	opts *bind.TransactOpts,
+	srcChainId, dstChainId int,
+	amount *big.Int,
+	deadline time.Time,
+) (*types.Transaction, error) {
+	src, ok := h.chains[srcChainId]
+	if !ok {
+		return nil, fmt.Errorf("bridge: chain %d not configured for bridging", srcChainId)
+	}
+	dst, ok := h.chains[dstChainId]
+	if !ok {
+		return nil, fmt.Errorf("bridge: chain %d not configured for bridging", dstChainId)
+	}
+
+	bonderFee, srcAmountOutMin, dstAmountOutMin, err := h.quote(src, dst, amount)
+	if err != nil {
+		return nil, fmt.Errorf("bridge: failed to quote transfer: %v", err)
+	}
+	deadlineBig := big.NewInt(deadline.Unix())
+
+	switch {
+	case src.config.isL1():
+		// L1 -> L2: sendToL2 mints hTokens directly to the recipient, or
+		// swaps them for the canonical token via the destination AMM
+		// wrapper if dstAmountOutMin is non-zero.
+		return src.l1Bridge.SendToL2(
+			opts,
+			big.NewInt(int64(dstChainId)),
+			opts.From,
+			amount,
+			dstAmountOutMin,
+			deadlineBig,
+			common.Address{},
+			big.NewInt(0),
+		)
+	case dst.config.isL1():
+		// L2 -> L1: swapAndSend on the source AMM wrapper swaps the
+		// canonical token for hTokens against the source pool, then sends
+		// to L1, where the L1 bridge burns 1:1 with no destination swap.
+		return src.ammWrapper.SwapAndSend(
+			opts,
+			big.NewInt(int64(dstChainId)),
+			opts.From,
+			amount,
+			bonderFee,
+			srcAmountOutMin,
+			deadlineBig,
+			big.NewInt(0),
+			big.NewInt(0),
+		)
+	default:
+		// L2 -> L2: same call shape as L2 -> L1, but the trailing
+		// amountOutMin/deadline pair now bounds the destination AMM's
+		// hToken -> canonical swap instead of being unused.
+		return src.ammWrapper.SwapAndSend(
+			opts,
+			big.NewInt(int64(dstChainId)),
+			opts.From,
+			amount,
+			bonderFee,
+			srcAmountOutMin,
+			deadlineBig,
+			dstAmountOutMin,
+			deadlineBig,
+		)
+	}
+}
+
+// bonderFeeBps is the bonder's cut of the transfer, matching Hop's default
+// AMM bonder fee share. The bonder's minimum, queried from the destination
+// bridge, always wins if it's higher - below that, no bonder will front the
+// instant withdrawal and the transfer will sit unbonded until challenged.
+const bonderFeeBps = 4
+
+// quote computes the bonder fee and the minimum acceptable output of each
+// AMM leg of the transfer, pricing the source and destination pools
+// independently since they are different pools with different liquidity.
+func (h *Hop) quote(src, dst *hopChain, amount *big.Int) (bonderFee, srcAmountOutMin, dstAmountOutMin *big.Int, err error) {
+	bonderFee = big.NewInt(0)
+	if !dst.config.isL1() && dst.l2Bridge != nil {
+		bonderFee = new(big.Int).Div(new(big.Int).Mul(amount, big.NewInt(bonderFeeBps)), big.NewInt(10_000))
+		minBonderFee, err := dst.l2Bridge.MinBonderFeeAbsolute(nil)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to fetch min bonder fee on destination chain: %v", err)
+		}
+		if minBonderFee.Cmp(bonderFee) > 0 {
+			bonderFee = minBonderFee
+		}
+	}
+
+	srcAmountOutMin = big.NewInt(0)
+	if src.saddleSwap != nil {
+		// The token indices passed to quoteSwap follow Hop's SaddleSwap pool
+		// convention: index 0 is the hToken side, index 1 the canonical
+		// token. The source leg swaps canonical -> hToken before sending.
+		srcAmountOutMin, err = quoteSwap(src.saddleSwap, 1, 0, amount)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to price source swap: %v", err)
+		}
+	}
+
+	dstAmountOutMin = big.NewInt(0)
+	if dst.saddleSwap != nil {
+		// The destination leg swaps the bonded hToken -> canonical token,
+		// net of the bonder fee taken out on the way.
+		amountAfterFee := new(big.Int).Sub(amount, bonderFee)
+		dstAmountOutMin, err = quoteSwap(dst.saddleSwap, 0, 1, amountAfterFee)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to price destination swap: %v", err)
+		}
+	}
+
+	return bonderFee, srcAmountOutMin, dstAmountOutMin, nil
+}
+
+// quoteSwap prices swapping amount from tokenIndexFrom to tokenIndexTo in
+// pool and applies a 0.5% slippage allowance on top of its quote.
+func quoteSwap(pool *HopL2SaddleSwap, tokenIndexFrom, tokenIndexTo uint8, amount *big.Int) (*big.Int, error) {
+	out, err := pool.CalculateSwap(nil, tokenIndexFrom, tokenIndexTo, amount)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Div(new(big.Int).Mul(out, big.NewInt(995)), big.NewInt(1000)), nil
+}
+
+// TransferId extracts the TransferSent event's transferId from the receipt
+// of the source-chain leg returned by Send, by locating the log emitted by
+// the source bridge contract and decoding it with the generated event
+// parser - log ordering and topic layout are contract details this sidesteps.
+func (h *Hop) TransferId(srcChainId int, receipt *types.Receipt) (common.Hash, error) {
+	src, ok := h.chains[srcChainId]
+	if !ok {
+		return common.Hash{}, fmt.Errorf("bridge: chain %d not configured for bridging", srcChainId)
+	}
+
+	for _, vLog := range receipt.Logs {
+		if src.config.isL1() {
+			if vLog.Address != src.config.L1BridgeAddress {
+				continue
+			}
+			evt, err := src.l1Bridge.ParseTransferSentToL2(*vLog)
+			if err != nil {
+				continue
+			}
+			return evt.TransferId, nil
+		}
+		if vLog.Address != src.config.L2BridgeAddress {
+			continue
+		}
+		evt, err := src.l2Bridge.ParseTransferSent(*vLog)
+		if err != nil {
+			continue
+		}
+		return evt.TransferId, nil
+	}
+
+	return common.Hash{}, fmt.Errorf("bridge: no TransferSent log from chain %d in receipt %s", srcChainId, receipt.TxHash)
+}
+
+// WaitForBondedTransfer polls dstChainId for the bonder's WithdrawalBonded
+// event matching transferId and returns the bonding transaction hash once
+// it lands.
+func (h *Hop) WaitForBondedTransfer(ctx context.Context, dstChainId int, transferId common.Hash) (common.Hash, error) {
+	dst, ok := h.chains[dstChainId]
+	if !ok {
+		return common.Hash{}, fmt.Errorf("bridge: chain %d not configured for bridging", dstChainId)
+	}
+	if dst.l2Bridge == nil {
+		return common.Hash{}, fmt.Errorf("bridge: chain %d has no L2 bridge to watch for bonded transfers", dstChainId)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, bondedTransferTimeout)
+	defer cancel()
+
+	head, err := dst.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("bridge: failed to fetch starting block on chain %d: %v", dstChainId, err)
+	}
+	fromBlock := head.Number.Uint64()
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for {
+		// Start narrows each poll to the blocks not yet scanned, instead of
+		// re-scanning from genesis every tick. End is the head fetched
+		// before the poll, and Start only moves up to it once the poll
+		// succeeds, so a transfer bonded in the block the previous poll
+		// stopped at, or in any block mined during that poll, is never skipped.
+		if head, err := dst.client.HeaderByNumber(ctx, nil); err == nil {
+			toBlock := head.Number.Uint64()
+			iter, err := dst.l2Bridge.FilterWithdrawalBonded(&bind.FilterOpts{Start: fromBlock, End: &toBlock, Context: ctx}, [][32]byte{[32]byte(transferId)})
+			if err == nil {
+				for iter.Next() {
+					txHash := iter.Event.Raw.TxHash
+					iter.Close()
+					return txHash, nil
+				}
+				iter.Close()
+				fromBlock = toBlock
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return common.Hash{}, fmt.Errorf("bridge: timed out waiting for bonded transfer %s on chain %d", transferId, dstChainId)
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"api/src/bridge"
 	"api/src/config"
 	"api/src/utils"
 	"context"
@@ -10,24 +11,19 @@ import (
 	"math/big"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 )
 
-const DefaultTxTimeout = time.Second * 15
-
 type Chain struct {
 	InteractionAddress common.Address
 	FeederAddress      common.Address
 	USDTAddress        common.Address
-	Client             *ethclient.Client
+	Client             RPCClient
 	Interaction        *Interaction
 	Feeder             *Feeder
 	Fees               *Fees
@@ -43,6 +39,23 @@ type Interactor struct {
 	Chains map[int]*Chain
 
 	GasMultiplier float64
+
+	// GasTipMultiplier scales the node-suggested priority fee (SuggestGasTipCap)
+	// before it is used as auth.GasTipCap on EIP-1559 chains.
+	GasTipMultiplier float64
+	// BaseFeeMultiplier scales the latest block's base fee when deriving
+	// auth.GasFeeCap, so that fee keeps up if a couple more blocks are full
+	// before the tx lands: feeCap = baseFee*BaseFeeMultiplier + tip.
+	BaseFeeMultiplier float64
+
+	// Bridge moves USDT across interactor.Chains over the Hop protocol. Nil
+	// if no chain in the config opted into bridging.
+	Bridge *bridge.Hop
+
+	// NonceManager serializes nonce issuance across concurrent sends and
+	// tracks in-flight txs so they can be bumped. Every outbound tx goes
+	// through it via getAuth.
+	NonceManager *NonceManager
 }
 
 func NewInteractor(config *config.Config) *Interactor {
@@ -59,7 +72,11 @@ func NewInteractor(config *config.Config) *Interactor {
 
 	chains := make(map[int]*Chain)
 	for _, chainConfig := range config.Blockchains {
-		client, err := ethclient.Dial(chainConfig.NodeAddress)
+		nodeAddresses := chainConfig.NodeAddresses
+		if len(nodeAddresses) == 0 {
+			nodeAddresses = []string{chainConfig.NodeAddress}
+		}
+		client, err := NewMultiRPCClient(int64(chainConfig.ChainId), nodeAddresses)
 		if err != nil {
 			log.Fatalf("Node dial failed: %v", err)
 		}
@@ -105,16 +122,82 @@ func NewInteractor(config *config.Config) *Interactor {
 		chains[chainConfig.ChainId] = &chain
 	}
 
-	interactor := Interactor{
-		PrivateKey:    privateKey,
-		UserAddress:   crypto.PubkeyToAddress(*publicKeyECDSA),
-		Chains:        chains,
-		GasMultiplier: 1.1,
+	hopChains := make(map[int]bridge.ChainConfig)
+	hopClients := make(map[int]bind.ContractBackend)
+	for _, chainConfig := range config.Blockchains {
+		if chainConfig.Hop == nil {
+			continue
+		}
+		hopChains[chainConfig.ChainId] = bridge.ChainConfig{
+			ChainId:             chainConfig.ChainId,
+			L1BridgeAddress:     common.HexToAddress(chainConfig.Hop.L1BridgeAddress),
+			L2BridgeAddress:     common.HexToAddress(chainConfig.Hop.L2BridgeAddress),
+			L2AmmWrapperAddress: common.HexToAddress(chainConfig.Hop.L2AmmWrapperAddress),
+			L2SaddleSwapAddress: common.HexToAddress(chainConfig.Hop.L2SaddleSwapAddress),
+		}
+		hopClients[chainConfig.ChainId] = chains[chainConfig.ChainId].Client
+	}
+	var hop *bridge.Hop
+	if len(hopChains) > 0 {
+		hop, err = bridge.NewHop(hopChains, hopClients)
+		if err != nil {
+			log.Fatalf("Failed to init hop bridge: %v", err)
+		}
 	}
+
+	interactor := &Interactor{
+		PrivateKey:        privateKey,
+		UserAddress:       crypto.PubkeyToAddress(*publicKeyECDSA),
+		Chains:            chains,
+		GasMultiplier:     1.1,
+		GasTipMultiplier:  1.1,
+		BaseFeeMultiplier: 2,
+		Bridge:            hop,
+	}
+	interactor.NonceManager = NewNonceManager(interactor)
 	log.Printf("Interaction inited with user address: %s\n",
 		interactor.UserAddress,
 	)
-	return &interactor
+	return interactor
+}
+
+// BridgeUSDT moves amount of USDT from srcChainId to dstChainId over the
+// Hop protocol, without the user having to bridge manually. It submits the
+// source-chain leg, then blocks until the bonder's transfer lands on the
+// destination chain.
+func (interactor *Interactor) BridgeUSDT(ctx context.Context, srcChainId, dstChainId int, amount *big.Int, deadline time.Time) (srcTxHash, dstTxHash common.Hash, err error) {
+	if interactor.Bridge == nil {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("bridge: no hop chains configured")
+	}
+
+	opts, err := interactor.getAuth(srcChainId)
+	if err != nil {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("failed to get opts for bridge send: %v", err)
+	}
+
+	tx, err := interactor.Bridge.Send(ctx, opts, srcChainId, dstChainId, amount, deadline)
+	if err != nil {
+		interactor.NonceManager.Release(srcChainId, interactor.UserAddress, opts.Nonce.Uint64())
+		return common.Hash{}, common.Hash{}, fmt.Errorf("failed to submit bridge send: %v", err)
+	}
+	interactor.NonceManager.Track(srcChainId, interactor.UserAddress, opts.Nonce.Uint64(), tx)
+
+	srcClient := interactor.Chains[srcChainId].Client
+	receipt, err := waitTxConfirmed(ctx, srcClient, tx)
+	if err != nil {
+		return tx.Hash(), common.Hash{}, err
+	}
+	transferId, err := interactor.Bridge.TransferId(srcChainId, receipt)
+	if err != nil {
+		return tx.Hash(), common.Hash{}, err
+	}
+
+	dstTxHash, err = interactor.Bridge.WaitForBondedTransfer(ctx, dstChainId, transferId)
+	if err != nil {
+		return tx.Hash(), common.Hash{}, err
+	}
+
+	return tx.Hash(), dstTxHash, nil
 }
 
 func (interactor *Interactor) GetChain(chainId int) *Chain {
@@ -122,8 +205,7 @@ func (interactor *Interactor) GetChain(chainId int) *Chain {
 }
 
 func (interactor *Interactor) getAuth(chainId int) (*bind.TransactOpts, error) {
-
-	nonce, err := interactor.Chains[chainId].Client.PendingNonceAt(context.Background(), interactor.UserAddress)
+	nonce, err := interactor.NonceManager.Next(context.Background(), chainId, interactor.UserAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -134,45 +216,56 @@ func (interactor *Interactor) getAuth(chainId int) (*bind.TransactOpts, error) {
 		return nil, err
 	}
 	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)      // in wei
-	gasPrice, err := interactor.Chains[chainId].Client.SuggestGasPrice(context.Background())
-	if err != nil {
+	auth.Value = big.NewInt(0) // in wei
+
+	if err := interactor.setGasFees(context.Background(), chainId, auth); err != nil {
 		return nil, err
 	}
-	auth.GasPrice = gasPrice
 
 	return auth, nil
 }
 
-// Returns a channel that blocks until the transaction is confirmed
-func waitTxConfirmed(c *ethclient.Client, hash common.Hash) error {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultTxTimeout)
-	defer cancel()
-	queryTicker := time.NewTicker(time.Second)
-	defer queryTicker.Stop()
-	for {
-		_, err := c.TransactionReceipt(ctx, hash)
-		if err == nil {
-			fmt.Printf("Tx: %s mined\n", hash.String())
-			return nil
-		}
+// setGasFees fills in auth.GasPrice or auth.GasFeeCap/auth.GasTipCap depending
+// on whether the chain's latest block carries a base fee (EIP-1559 support).
+// Falling back to a legacy GasPrice keeps pre-1559 chains working unchanged.
+func (interactor *Interactor) setGasFees(ctx context.Context, chainId int, auth *bind.TransactOpts) error {
+	client := interactor.Chains[chainId].Client
 
-		if errors.Is(err, ethereum.NotFound) {
-			fmt.Print("Transaction not yet mined\n")
-		} else {
-			fmt.Printf("Receipt retrieval failed: %s\n", err.Error())
-		}
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
 
-		// Wait for the next round.
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-queryTicker.C:
+	if head.BaseFee == nil {
+		gasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return err
 		}
+		auth.GasPrice = gasPrice
+		return nil
+	}
+
+	tip, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return err
 	}
+	tip = mulFloat(tip, interactor.GasTipMultiplier)
+
+	feeCap := new(big.Int).Add(mulFloat(head.BaseFee, interactor.BaseFeeMultiplier), tip)
+
+	auth.GasTipCap = tip
+	auth.GasFeeCap = feeCap
+	return nil
+}
+
+// mulFloat scales a wei amount by a float multiplier, rounding down.
+func mulFloat(amount *big.Int, multiplier float64) *big.Int {
+	scaled := new(big.Float).Mul(new(big.Float).SetInt(amount), big.NewFloat(multiplier))
+	result, _ := scaled.Int(nil)
+	return result
 }
 
-func (interactor *Interactor) WithdrawMultiple(fundId *big.Int, tradeTvl *big.Int, chainId int) error {
+func (interactor *Interactor) WithdrawMultiple(ctx context.Context, fundId *big.Int, tradeTvl *big.Int, chainId int) error {
 	users, err := interactor.Chains[chainId].Feeder.UserWaitingForWithdrawal(nil, fundId)
 	if err != nil {
 		return err
@@ -183,9 +276,11 @@ func (interactor *Interactor) WithdrawMultiple(fundId *big.Int, tradeTvl *big.In
 	}
 	tx, err := interactor.Chains[chainId].Interaction.WithdrawMultiple(opts, fundId, users, tradeTvl)
 	if err != nil {
+		interactor.NonceManager.Release(chainId, interactor.UserAddress, opts.Nonce.Uint64())
 		return err
 	}
-	err = waitTxConfirmed(interactor.Chains[chainId].Client, tx.Hash())
+	interactor.NonceManager.Track(chainId, interactor.UserAddress, opts.Nonce.Uint64(), tx)
+	_, err = waitTxConfirmed(ctx, interactor.Chains[chainId].Client, tx)
 	if err != nil {
 		return err
 	}
@@ -207,6 +302,7 @@ func (interactor *Interactor) UserData(chainId int, fundId *big.Int, user common
 
 // Pack multiple swaps in one array
 func (interactor *Interactor) MultiSwap(
+	ctx context.Context,
 	tradingAddress string,
 	swapAddresses []string,
 	tokensA []string,
@@ -253,9 +349,11 @@ func (interactor *Interactor) MultiSwap(
 		multiSwapData,
 	)
 	if err != nil {
+		interactor.NonceManager.Release(chainId, interactor.UserAddress, opts.Nonce.Uint64())
 		return err
 	}
-	err = waitTxConfirmed(interactor.Chains[chainId].Client, tx.Hash())
+	interactor.NonceManager.Track(chainId, interactor.UserAddress, opts.Nonce.Uint64(), tx)
+	_, err = waitTxConfirmed(ctx, interactor.Chains[chainId].Client, tx)
 	if err != nil {
 		return err
 	}
@@ -292,6 +390,7 @@ func (interactor *Interactor) AAVEPositions(
 }
 
 func (interactor *Interactor) AAVEWithdraw(
+	ctx context.Context,
 	token common.Address,
 	amount decimal.Decimal,
 	tradingAddress common.Address,
@@ -307,9 +406,11 @@ func (interactor *Interactor) AAVEWithdraw(
 	}
 	tx, err := tradeContract.AaveWithdraw(opts, token, amount.BigInt())
 	if err != nil {
+		interactor.NonceManager.Release(chainId, interactor.UserAddress, opts.Nonce.Uint64())
 		return fmt.Errorf("failed to execute aave withdraw: %v", err)
 	}
-	err = waitTxConfirmed(interactor.Chains[chainId].Client, tx.Hash())
+	interactor.NonceManager.Track(chainId, interactor.UserAddress, opts.Nonce.Uint64(), tx)
+	_, err = waitTxConfirmed(ctx, interactor.Chains[chainId].Client, tx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,261 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// RPCClient is the subset of *ethclient.Client's behavior that this package
+// and the generated contract bindings rely on. Both *ethclient.Client and
+// *MultiRPCClient satisfy it, so Chain.Client can hold either.
+type RPCClient interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// endpointHealthCheckInterval is how often MultiRPCClient re-probes every
+// endpoint's latest block height in the background. Chain ID is only
+// checked once, when NewMultiRPCClient dials the endpoint.
+const endpointHealthCheckInterval = 15 * time.Second
+
+// endpoint tracks the health of a single node so MultiRPCClient can route
+// calls away from ones that are stale or erroring.
+type endpoint struct {
+	url    string
+	client *ethclient.Client
+
+	mu          sync.Mutex
+	lastBlock   uint64
+	lastChecked time.Time
+	errStreak   int
+	dead        bool
+}
+
+func (e *endpoint) score() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.dead {
+		return -1
+	}
+	return int(e.lastBlock) - e.errStreak*1_000_000
+}
+
+func (e *endpoint) recordSuccess(block uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastBlock = block
+	e.lastChecked = time.Now()
+	e.errStreak = 0
+	e.dead = false
+}
+
+// recordHealthy clears the error streak after a successful call, without
+// touching lastBlock - that's refreshHealth's job, and reading it here to
+// hand back to recordSuccess would race with refreshHealth's own writes.
+func (e *endpoint) recordHealthy() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errStreak = 0
+	e.dead = false
+}
+
+func (e *endpoint) recordError() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errStreak++
+	if e.errStreak >= 5 {
+		e.dead = true
+	}
+}
+
+// MultiRPCClient fans a single chain's calls out over several node URLs,
+// routing each call to the healthiest endpoint and retrying the remaining
+// ones on failure. It implements the same interface as *ethclient.Client
+// (RPCClient), so it can be dropped into Chain.Client unchanged.
+type MultiRPCClient struct {
+	chainId   int64
+	endpoints []*endpoint
+
+	mu       sync.Mutex
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewMultiRPCClient dials every url in urls, verifies each reports chainId,
+// and starts background health checks. At least one working endpoint is
+// required.
+func NewMultiRPCClient(chainId int64, urls []string) (*MultiRPCClient, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("multirpc: no node addresses configured for chain %d", chainId)
+	}
+
+	m := &MultiRPCClient{
+		chainId: chainId,
+		stopCh:  make(chan struct{}),
+	}
+
+	for _, url := range urls {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			fmt.Printf("multirpc: failed to dial %s: %v\n", url, err)
+			continue
+		}
+		gotId, err := client.ChainID(context.Background())
+		if err != nil {
+			fmt.Printf("multirpc: failed to fetch chain id from %s: %v\n", url, err)
+			continue
+		}
+		if gotId.Cmp(big.NewInt(chainId)) != 0 {
+			fmt.Printf("multirpc: %s reports chain id %s, expected %d, skipping\n", url, gotId, chainId)
+			continue
+		}
+		m.endpoints = append(m.endpoints, &endpoint{url: url, client: client})
+	}
+
+	if len(m.endpoints) == 0 {
+		return nil, fmt.Errorf("multirpc: no usable endpoints for chain %d", chainId)
+	}
+
+	m.refreshHealth()
+	go m.healthLoop()
+
+	return m, nil
+}
+
+func (m *MultiRPCClient) healthLoop() {
+	ticker := time.NewTicker(endpointHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.refreshHealth()
+		}
+	}
+}
+
+func (m *MultiRPCClient) refreshHealth() {
+	for _, ep := range m.endpoints {
+		ep := ep
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			head, err := ep.client.HeaderByNumber(ctx, nil)
+			if err != nil {
+				ep.recordError()
+				return
+			}
+			ep.recordSuccess(head.Number.Uint64())
+		}()
+	}
+}
+
+// Close stops background health checks. It does not close the underlying
+// endpoint clients, since they may still be draining in-flight calls.
+func (m *MultiRPCClient) Close() {
+	m.stopOnce.Do(func() { close(m.stopCh) })
+}
+
+// ordered returns endpoints from best to worst, by last known health score.
+func (m *MultiRPCClient) ordered() []*endpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ordered := make([]*endpoint, len(m.endpoints))
+	copy(ordered, m.endpoints)
+	for i := 1; i < len(ordered); i++ {
+		for j := i; j > 0 && ordered[j].score() > ordered[j-1].score(); j-- {
+			ordered[j], ordered[j-1] = ordered[j-1], ordered[j]
+		}
+	}
+	return ordered
+}
+
+// call runs fn against the best endpoint first, then falls back to the
+// others in health order until one succeeds or all have been tried.
+func call[T any](m *MultiRPCClient, fn func(*ethclient.Client) (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+	for _, ep := range m.ordered() {
+		result, err := fn(ep.client)
+		if err == nil {
+			ep.recordHealthy()
+			return result, nil
+		}
+		ep.recordError()
+		lastErr = err
+	}
+	return zero, fmt.Errorf("multirpc: all endpoints failed for chain %d: %w", m.chainId, lastErr)
+}
+
+func (m *MultiRPCClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return call(m, func(c *ethclient.Client) ([]byte, error) { return c.CodeAt(ctx, account, blockNumber) })
+}
+
+func (m *MultiRPCClient) CallContract(ctx context.Context, call_ ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return call(m, func(c *ethclient.Client) ([]byte, error) { return c.CallContract(ctx, call_, blockNumber) })
+}
+
+func (m *MultiRPCClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return call(m, func(c *ethclient.Client) ([]byte, error) { return c.PendingCodeAt(ctx, account) })
+}
+
+func (m *MultiRPCClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return call(m, func(c *ethclient.Client) (uint64, error) { return c.PendingNonceAt(ctx, account) })
+}
+
+func (m *MultiRPCClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return call(m, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasPrice(ctx) })
+}
+
+func (m *MultiRPCClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return call(m, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasTipCap(ctx) })
+}
+
+func (m *MultiRPCClient) EstimateGas(ctx context.Context, call_ ethereum.CallMsg) (uint64, error) {
+	return call(m, func(c *ethclient.Client) (uint64, error) { return c.EstimateGas(ctx, call_) })
+}
+
+func (m *MultiRPCClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(m, func(c *ethclient.Client) (*types.Header, error) { return c.HeaderByNumber(ctx, number) })
+}
+
+func (m *MultiRPCClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return call(m, func(c *ethclient.Client) (*types.Receipt, error) { return c.TransactionReceipt(ctx, txHash) })
+}
+
+func (m *MultiRPCClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return call(m, func(c *ethclient.Client) ([]types.Log, error) { return c.FilterLogs(ctx, q) })
+}
+
+func (m *MultiRPCClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return call(m, func(c *ethclient.Client) (ethereum.Subscription, error) { return c.SubscribeFilterLogs(ctx, q, ch) })
+}
+
+// SendTransaction broadcasts tx to every endpoint rather than just the best
+// one, so a lagging or misbehaving node doesn't silently swallow it.
+func (m *MultiRPCClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	var lastErr error
+	sent := 0
+	for _, ep := range m.ordered() {
+		if err := ep.client.SendTransaction(ctx, tx); err != nil {
+			ep.recordError()
+			lastErr = err
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		return fmt.Errorf("multirpc: failed to broadcast tx %s to any endpoint for chain %d: %w", tx.Hash(), m.chainId, lastErr)
+	}
+	return nil
+}
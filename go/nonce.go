@@ -0,0 +1,248 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// nonceReapInterval is how often the background reaper checks whether an
+// in-flight tx's receipt has landed.
+const nonceReapInterval = 10 * time.Second
+
+type nonceKey struct {
+	chainId int
+	user    common.Address
+}
+
+// inFlightTx represents one nonce reserved by Next. tx and hash are zero
+// until Track supplies the broadcast transaction - the entry exists from
+// the moment Next hands the nonce out. Next itself never reads it; duplicate
+// nonces are prevented by advancing nonceAccount.next under its mutex.
+type inFlightTx struct {
+	tx   *types.Transaction
+	hash common.Hash
+}
+
+// nonceAccount tracks the next nonce to hand out and every in-flight tx for
+// one (chain, address) pair.
+type nonceAccount struct {
+	mu       sync.Mutex
+	next     uint64
+	seeded   bool
+	inFlight map[uint64]*inFlightTx
+}
+
+// NonceManager hands out the next nonce per (chain, address) behind a
+// mutex, so concurrent sends against the same account (WithdrawMultiple,
+// MultiSwap and AAVEWithdraw can all fire at once) never race for the same
+// nonce. It also remembers each nonce's last broadcast tx so a stuck one
+// can be bumped and rebroadcast via Interactor.BumpTx, and reaps entries in
+// the background once their receipt lands.
+type NonceManager struct {
+	interactor *Interactor
+
+	mu       sync.Mutex
+	accounts map[nonceKey]*nonceAccount
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewNonceManager starts a NonceManager for interactor and its background
+// reaper. Call Close when the interactor is done with it.
+func NewNonceManager(interactor *Interactor) *NonceManager {
+	m := &NonceManager{
+		interactor: interactor,
+		accounts:   make(map[nonceKey]*nonceAccount),
+		stopCh:     make(chan struct{}),
+	}
+	go m.reapLoop()
+	return m
+}
+
+func (m *NonceManager) accountFor(chainId int, user common.Address) *nonceAccount {
+	key := nonceKey{chainId, user}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	acc, ok := m.accounts[key]
+	if !ok {
+		acc = &nonceAccount{inFlight: make(map[uint64]*inFlightTx)}
+		m.accounts[key] = acc
+	}
+	return acc
+}
+
+// Next returns the next nonce to use for (chainId, user) and immediately
+// records it in acc.inFlight, before any caller can build or broadcast a
+// tx with it. Advancing acc.next under acc.mu, not PendingNonceAt, is what
+// keeps two concurrent Next calls from ever handing out the same nonce. It
+// reconciles with PendingNonceAt the first time an account is seen, and
+// any later time the node reports a pending nonce past what we're
+// tracking (a gap left by some other process or a restart); a reserved
+// nonce is only ever given back explicitly, via Release.
+func (m *NonceManager) Next(ctx context.Context, chainId int, user common.Address) (uint64, error) {
+	acc := m.accountFor(chainId, user)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.seeded {
+		pending, err := m.interactor.Chains[chainId].Client.PendingNonceAt(ctx, user)
+		if err != nil {
+			return 0, err
+		}
+		acc.next = pending
+		acc.seeded = true
+	} else if pending, err := m.interactor.Chains[chainId].Client.PendingNonceAt(ctx, user); err == nil && pending > acc.next {
+		acc.next = pending
+	}
+
+	nonce := acc.next
+	acc.next++
+	acc.inFlight[nonce] = &inFlightTx{}
+	return nonce, nil
+}
+
+// Track records tx as the broadcast transaction for a nonce reserved by
+// Next, so BumpTx can later rebroadcast it with a higher fee, and the
+// reaper can drop it once it's mined.
+func (m *NonceManager) Track(chainId int, user common.Address, nonce uint64, tx *types.Transaction) {
+	acc := m.accountFor(chainId, user)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	acc.inFlight[nonce] = &inFlightTx{tx: tx, hash: tx.Hash()}
+}
+
+// Release gives back a nonce reserved by Next that the caller has
+// confirmed was never broadcast - e.g. getAuth succeeded but the
+// subsequent contract call returned an error before reaching Track. Only
+// the most recently issued nonce is handed out again by a later Next;
+// releasing an earlier one just drops its in-flight entry, and Next does
+// not reissue it. Only call this when certain no tx went out with this
+// nonce; releasing the most recent one after it was actually sent would
+// let a later Next hand it out again.
+func (m *NonceManager) Release(chainId int, user common.Address, nonce uint64) {
+	acc := m.accountFor(chainId, user)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	delete(acc.inFlight, nonce)
+	if nonce == acc.next-1 {
+		acc.next = nonce
+	}
+}
+
+func (m *NonceManager) reapLoop() {
+	ticker := time.NewTicker(nonceReapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.reapOnce()
+		}
+	}
+}
+
+// reapOnce drops any in-flight entry whose receipt has landed, successful
+// or reverted either way - BumpTx only needs to see still-pending ones.
+func (m *NonceManager) reapOnce() {
+	m.mu.Lock()
+	keys := make([]nonceKey, 0, len(m.accounts))
+	for key := range m.accounts {
+		keys = append(keys, key)
+	}
+	m.mu.Unlock()
+
+	for _, key := range keys {
+		chain, ok := m.interactor.Chains[key.chainId]
+		if !ok {
+			continue
+		}
+		acc := m.accountFor(key.chainId, key.user)
+
+		acc.mu.Lock()
+		pending := make(map[uint64]common.Hash, len(acc.inFlight))
+		for nonce, inFlight := range acc.inFlight {
+			if inFlight.tx == nil {
+				continue // reserved but not yet broadcast; nothing to check yet
+			}
+			pending[nonce] = inFlight.hash
+		}
+		acc.mu.Unlock()
+
+		for nonce, hash := range pending {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_, err := chain.Client.TransactionReceipt(ctx, hash)
+			cancel()
+			if err != nil {
+				continue
+			}
+			acc.mu.Lock()
+			delete(acc.inFlight, nonce)
+			acc.mu.Unlock()
+		}
+	}
+}
+
+// Close stops the background reaper.
+func (m *NonceManager) Close() {
+	m.stopOnce.Do(func() { close(m.stopCh) })
+}
+
+// BumpTx rebroadcasts the tx tracked at (chainId, nonce) with its fee
+// fields bumped by the interactor's gas multipliers, so a stuck transaction
+// can get through without losing its place in line.
+func (interactor *Interactor) BumpTx(chainId int, nonce uint64) error {
+	acc := interactor.NonceManager.accountFor(chainId, interactor.UserAddress)
+	acc.mu.Lock()
+	inFlight, ok := acc.inFlight[nonce]
+	acc.mu.Unlock()
+	if !ok || inFlight.tx == nil {
+		return fmt.Errorf("noncemanager: no in-flight tx for chain %d nonce %d", chainId, nonce)
+	}
+
+	chainIdBig := big.NewInt(int64(chainId))
+	signer := types.LatestSignerForChainID(chainIdBig)
+
+	var replacement *types.Transaction
+	if inFlight.tx.Type() == types.DynamicFeeTxType {
+		replacement = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainIdBig,
+			Nonce:     nonce,
+			GasTipCap: mulFloat(inFlight.tx.GasTipCap(), interactor.GasTipMultiplier),
+			GasFeeCap: mulFloat(inFlight.tx.GasFeeCap(), interactor.BaseFeeMultiplier),
+			Gas:       inFlight.tx.Gas(),
+			To:        inFlight.tx.To(),
+			Value:     inFlight.tx.Value(),
+			Data:      inFlight.tx.Data(),
+		})
+	} else {
+		replacement = types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: mulFloat(inFlight.tx.GasPrice(), interactor.GasMultiplier),
+			Gas:      inFlight.tx.Gas(),
+			To:       inFlight.tx.To(),
+			Value:    inFlight.tx.Value(),
+			Data:     inFlight.tx.Data(),
+		})
+	}
+
+	signed, err := types.SignTx(replacement, signer, interactor.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("noncemanager: failed to sign bumped tx: %v", err)
+	}
+
+	client := interactor.Chains[chainId].Client
+	if err := client.SendTransaction(context.Background(), signed); err != nil {
+		return fmt.Errorf("noncemanager: failed to rebroadcast bumped tx: %v", err)
+	}
+
+	interactor.NonceManager.Track(chainId, interactor.UserAddress, nonce, signed)
+	return nil
+}
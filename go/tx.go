@@ -0,0 +1,163 @@
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// revertErrorSelector/revertPanicSelector are the first 4 bytes of the
+// keccak256 hash of the standard Error(string) and Panic(uint256)
+// signatures that Solidity encodes revert reasons with.
+const (
+	revertErrorSelector = "08c379a0"
+	revertPanicSelector = "4e487b71"
+)
+
+// waitTxConfirmedPollInterval is the initial delay between receipt polls;
+// it backs off exponentially, capped at waitTxConfirmedMaxPollInterval.
+const (
+	waitTxConfirmedPollInterval    = time.Second
+	waitTxConfirmedMaxPollInterval = 10 * time.Second
+)
+
+// TxRevertedError is returned by waitTxConfirmed when a transaction was
+// mined but reverted. Reason is populated when the revert used the
+// standard Error(string) selector; PanicCode is populated when it used
+// Panic(uint256). Both are empty when the contract reverted without data.
+type TxRevertedError struct {
+	Hash      common.Hash
+	Reason    string
+	PanicCode *big.Int
+}
+
+func (e *TxRevertedError) Error() string {
+	switch {
+	case e.Reason != "":
+		return fmt.Sprintf("tx %s reverted: %s", e.Hash, e.Reason)
+	case e.PanicCode != nil:
+		return fmt.Sprintf("tx %s reverted: panic(0x%x)", e.Hash, e.PanicCode)
+	default:
+		return fmt.Sprintf("tx %s reverted", e.Hash)
+	}
+}
+
+// waitTxConfirmed blocks until tx is mined or ctx is done. On success it
+// returns the receipt. On a reverted tx it re-runs the call at the mined
+// block to recover the revert reason and returns a *TxRevertedError.
+func waitTxConfirmed(ctx context.Context, c RPCClient, tx *types.Transaction) (*types.Receipt, error) {
+	hash := tx.Hash()
+	interval := waitTxConfirmedPollInterval
+	for {
+		receipt, err := c.TransactionReceipt(ctx, hash)
+		if err == nil {
+			if receipt.Status == types.ReceiptStatusFailed {
+				return receipt, revertError(ctx, c, tx, receipt)
+			}
+			fmt.Printf("Tx: %s mined\n", hash.String())
+			return receipt, nil
+		}
+
+		if errors.Is(err, ethereum.NotFound) {
+			fmt.Print("Transaction not yet mined\n")
+		} else {
+			fmt.Printf("Receipt retrieval failed: %s\n", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		if interval < waitTxConfirmedMaxPollInterval {
+			interval *= 2
+			if interval > waitTxConfirmedMaxPollInterval {
+				interval = waitTxConfirmedMaxPollInterval
+			}
+		}
+	}
+}
+
+// revertError re-executes tx as an eth_call pinned to the block it was
+// mined in, so the node returns the same revert data the transaction
+// produced, then decodes that data into a TxRevertedError.
+func revertError(ctx context.Context, c RPCClient, tx *types.Transaction, receipt *types.Receipt) error {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return &TxRevertedError{Hash: tx.Hash()}
+	}
+
+	data, callErr := c.CallContract(ctx, ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}, receipt.BlockNumber)
+	if callErr != nil {
+		data = extractRevertData(callErr)
+	}
+
+	return decodeRevert(tx.Hash(), data)
+}
+
+// extractRevertData pulls ABI-encoded revert data out of a JSON-RPC error,
+// if the node returned one (go-ethereum's rpc.DataError).
+func extractRevertData(err error) []byte {
+	type dataError interface {
+		ErrorData() interface{}
+	}
+	var de dataError
+	if !errors.As(err, &de) {
+		return nil
+	}
+	hexData, ok := de.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, err := hexutil.Decode(hexData)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func decodeRevert(hash common.Hash, data []byte) *TxRevertedError {
+	if len(data) < 4 {
+		return &TxRevertedError{Hash: hash}
+	}
+
+	selector := hex.EncodeToString(data[:4])
+	args := data[4:]
+
+	switch selector {
+	case revertErrorSelector:
+		stringType, _ := abi.NewType("string", "", nil)
+		unpacked, err := abi.Arguments{{Type: stringType}}.Unpack(args)
+		if err != nil || len(unpacked) == 0 {
+			return &TxRevertedError{Hash: hash}
+		}
+		reason, _ := unpacked[0].(string)
+		return &TxRevertedError{Hash: hash, Reason: reason}
+	case revertPanicSelector:
+		uintType, _ := abi.NewType("uint256", "", nil)
+		unpacked, err := abi.Arguments{{Type: uintType}}.Unpack(args)
+		if err != nil || len(unpacked) == 0 {
+			return &TxRevertedError{Hash: hash}
+		}
+		code, _ := unpacked[0].(*big.Int)
+		return &TxRevertedError{Hash: hash, PanicCode: code}
+	default:
+		return &TxRevertedError{Hash: hash}
+	}
+}